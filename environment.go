@@ -0,0 +1,30 @@
+package gemini
+
+// Environment selects which Gemini deployment a GeminiAPI or GeminiStream
+// talks to.
+type Environment int
+
+const (
+	EnvLive Environment = iota
+	EnvSandbox
+)
+
+// RESTBaseURL returns the REST API origin for the environment.
+func (e Environment) RESTBaseURL() string {
+	switch e {
+	case EnvSandbox:
+		return "https://api.sandbox.gemini.com"
+	default:
+		return "https://api.gemini.com"
+	}
+}
+
+// WSBaseURL returns the websocket origin for the environment.
+func (e Environment) WSBaseURL() string {
+	switch e {
+	case EnvSandbox:
+		return "wss://api.sandbox.gemini.com"
+	default:
+		return "wss://api.gemini.com"
+	}
+}
@@ -1,17 +1,13 @@
 package gemini
 
 import (
-	"crypto/hmac"
-	"crypto/sha512"
-	"encoding/base64"
-	"encoding/hex"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
-	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,8 +15,15 @@ type GeminiAPI struct {
 	BaseURL   string
 	ApiKey    string
 	ApiSecret string
-	Nonce     int64
-	logger    *log.Logger
+	Nonce     atomic.Int64
+	logger    Logger
+
+	httpClient     *http.Client
+	publicLimiter  RateLimiter
+	privateLimiter RateLimiter
+
+	symbolDetails   map[string]SymbolDetails
+	symbolDetailsMu sync.RWMutex
 }
 
 type GeminiError struct {
@@ -135,7 +138,7 @@ type OrderPlaceReq struct {
 	Side     string   `json:"side"`
 	Type     string   `json:"type"`
 	ClientId string   `json:"client_order_id"`
-	Options  []string `json:"options"`
+	Options  []string `json:"options,omitempty"`
 }
 
 func (r *OrderPlaceReq) GetPayload() []byte {
@@ -156,34 +159,39 @@ func (r *WithdrawReq) GetPayload() []byte {
 
 // AuthAPIReq makes a signed api request to gemini
 func (ga *GeminiAPI) AuthAPIReq(r Request) ([]byte, error) {
-	client := &http.Client{}
-	r.SetNonce(ga.Nonce)
-	ga.Nonce++
+	return ga.AuthAPIReqContext(context.Background(), r)
+}
+
+// AuthAPIReqContext is AuthAPIReq with a caller-supplied context, used to
+// cancel the request or bound it with a deadline.
+func (ga *GeminiAPI) AuthAPIReqContext(ctx context.Context, r Request) ([]byte, error) {
+	if err := ga.privateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	r.SetNonce(ga.Nonce.Add(1))
 	reqURL := fmt.Sprintf("%s%s", ga.BaseURL, r.GetRoute())
-	req, err := http.NewRequest("POST", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, nil)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to POST authenticated request to: %s\n", r.GetRoute())
-		return []byte{}, nil
+		ga.logger.Errorf("Failed to POST authenticated request to: %s\n", r.GetRoute())
+		return nil, err
 	}
 	payload := r.GetPayload()
-	ga.logger.Printf("Payload: %s\n", payload)
-	base64Payload := base64.StdEncoding.EncodeToString(payload)
-	h := hmac.New(sha512.New384, []byte(ga.ApiSecret))
-	h.Write([]byte(base64Payload))
-	sig := h.Sum(nil)
+	ga.logger.Debugf("Payload: %s\n", payload)
+	base64Payload, sig := signGeminiPayload(ga.ApiSecret, payload)
 	req.Header.Add("X-GEMINI-APIKEY", ga.ApiKey)
 	req.Header.Add("X-GEMINI-PAYLOAD", base64Payload)
-	req.Header.Add("X-GEMINI-SIGNATURE", hex.EncodeToString(sig))
-	resp, err := client.Do(req)
+	req.Header.Add("X-GEMINI-SIGNATURE", sig)
+	resp, err := ga.httpClient.Do(req)
 	if err != nil {
-		ga.logger.Printf("ERROR: failed to POST authenticated request: %s\n", r.GetRoute())
-		return []byte{}, nil
+		ga.logger.Errorf("failed to POST authenticated request: %s\n", r.GetRoute())
+		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		ga.logger.Printf("ERROR: failed to read response body\n")
-		return []byte{}, nil
+		ga.logger.Errorf("failed to read response body\n")
+		return nil, err
 	}
 
 	// check for error
@@ -191,7 +199,7 @@ func (ga *GeminiAPI) AuthAPIReq(r Request) ([]byte, error) {
 		geminiErr := &GeminiError{}
 		err = json.Unmarshal(body, geminiErr)
 		if err != nil {
-			ga.logger.Printf("ERROR: error decoding json response\n")
+			ga.logger.Errorf("error decoding json response\n")
 			return nil, err
 		}
 		geminiErr.StatusCode = resp.StatusCode
@@ -201,24 +209,59 @@ func (ga *GeminiAPI) AuthAPIReq(r Request) ([]byte, error) {
 	return body, nil
 }
 
-// GetTicker takes a ticker pair and returns a Ticker struct
-func (ga *GeminiAPI) GetTicker(pair string) (Ticker, error) {
-	tickerUrl := fmt.Sprintf("/v1/pubticker/%s", pair)
-	resp, err := http.Get(fmt.Sprintf("%s%s", ga.BaseURL, tickerUrl))
+// publicGet makes a rate-limited GET request against an unauthenticated
+// endpoint, using the shared http.Client, and returns the response body.
+func (ga *GeminiAPI) publicGet(ctx context.Context, path string) ([]byte, error) {
+	if err := ga.publicLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s%s", ga.BaseURL, path), nil)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to get ticker for pair %s\n", pair)
-		return Ticker{}, err
+		ga.logger.Errorf("Failed to build request to: %s\n", path)
+		return nil, err
+	}
+	resp, err := ga.httpClient.Do(req)
+	if err != nil {
+		ga.logger.Errorf("Failed to GET: %s\n", path)
+		return nil, err
 	}
 	defer resp.Body.Close()
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to read ticker from response\n")
+		ga.logger.Errorf("Failed to read response from: %s\n", path)
+		return nil, err
+	}
+
+	if resp.StatusCode > 399 {
+		geminiErr := &GeminiError{}
+		if err := json.Unmarshal(body, geminiErr); err != nil {
+			ga.logger.Errorf("error decoding json response\n")
+			return nil, err
+		}
+		geminiErr.StatusCode = resp.StatusCode
+		return nil, geminiErr
+	}
+
+	return body, nil
+}
+
+// GetTicker takes a ticker pair and returns a Ticker struct
+func (ga *GeminiAPI) GetTicker(pair string) (Ticker, error) {
+	return ga.GetTickerContext(context.Background(), pair)
+}
+
+// GetTickerContext is GetTicker with a caller-supplied context
+func (ga *GeminiAPI) GetTickerContext(ctx context.Context, pair string) (Ticker, error) {
+	body, err := ga.publicGet(ctx, fmt.Sprintf("/v1/pubticker/%s", pair))
+	if err != nil {
+		ga.logger.Errorf("Failed to get ticker for pair %s\n", pair)
 		return Ticker{}, err
 	}
 	ticker := Ticker{}
 	err = json.Unmarshal(body, &ticker)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to decode ticker from response\n")
+		ga.logger.Errorf("Failed to decode ticker from response\n")
 		return ticker, err
 	}
 	return ticker, nil
@@ -226,22 +269,21 @@ func (ga *GeminiAPI) GetTicker(pair string) (Ticker, error) {
 
 // GetOrderbook takes a currency symbol and returns a slice of Order structs
 func (ga *GeminiAPI) GetOrderbook(pair string, bidLimit, askLimit int) (Orderbook, error) {
-	tickerUrl := fmt.Sprintf("/v1/book/%s?limit_bids=%d&limit_asks=%d", pair, bidLimit, askLimit)
-	resp, err := http.Get(fmt.Sprintf("%s%s", ga.BaseURL, tickerUrl))
-	if err != nil {
-		ga.logger.Printf("ERROR: Failed to get ticker for pair %s\n", pair)
-		return Orderbook{}, err
-	}
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
+	return ga.GetOrderbookContext(context.Background(), pair, bidLimit, askLimit)
+}
+
+// GetOrderbookContext is GetOrderbook with a caller-supplied context
+func (ga *GeminiAPI) GetOrderbookContext(ctx context.Context, pair string, bidLimit, askLimit int) (Orderbook, error) {
+	path := fmt.Sprintf("/v1/book/%s?limit_bids=%d&limit_asks=%d", pair, bidLimit, askLimit)
+	body, err := ga.publicGet(ctx, path)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to read ticker from response\n")
+		ga.logger.Errorf("Failed to get orderbook for pair %s\n", pair)
 		return Orderbook{}, err
 	}
 	orders := Orderbook{}
 	err = json.Unmarshal(body, &orders)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to decode Orderbook from response: %s\n", body)
+		ga.logger.Errorf("Failed to decode Orderbook from response: %s\n", body)
 		return Orderbook{}, err
 	}
 	return orders, nil
@@ -249,16 +291,21 @@ func (ga *GeminiAPI) GetOrderbook(pair string, bidLimit, askLimit int) (Orderboo
 
 // GetFunds returns a list of Fund structs
 func (ga *GeminiAPI) GetFunds() ([]Fund, error) {
+	return ga.GetFundsContext(context.Background())
+}
+
+// GetFundsContext is GetFunds with a caller-supplied context
+func (ga *GeminiAPI) GetFundsContext(ctx context.Context) ([]Fund, error) {
 	input := NewBaseRequest("/v1/balances")
-	body, err := ga.AuthAPIReq(&input)
+	body, err := ga.AuthAPIReqContext(ctx, &input)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to get Funds\n")
+		ga.logger.Errorf("Failed to get Funds\n")
 		return []Fund{}, err
 	}
 	funds := []Fund{}
 	err = json.Unmarshal(body, &funds)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to get Funds\n")
+		ga.logger.Errorf("Failed to get Funds\n")
 		return []Fund{}, err
 	}
 	return funds, nil
@@ -267,38 +314,48 @@ func (ga *GeminiAPI) GetFunds() ([]Fund, error) {
 // Withdraw send the specified amount of funds of the specified
 // currency from your account to a specified address
 func (ga *GeminiAPI) Withdraw(currency, address string, amount float64) (*WithdrawResponse, error) {
+	return ga.WithdrawContext(context.Background(), currency, address, amount)
+}
+
+// WithdrawContext is Withdraw with a caller-supplied context
+func (ga *GeminiAPI) WithdrawContext(ctx context.Context, currency, address string, amount float64) (*WithdrawResponse, error) {
 	amountStr := fmt.Sprintf("%0.8f", amount)
 	input := &WithdrawReq{
 		BaseRequest: NewBaseRequest(fmt.Sprintf("/v1/withdraw/%s", currency)),
 		Address:     address,
 		Amount:      amountStr,
 	}
-	body, err := ga.AuthAPIReq(input)
+	body, err := ga.AuthAPIReqContext(ctx, input)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to withdraw\n")
+		ga.logger.Errorf("Failed to withdraw\n")
 		return nil, err
 	}
 	resp := &WithdrawResponse{}
 	err = json.Unmarshal(body, resp)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to withdraw\n")
+		ga.logger.Errorf("Failed to withdraw\n")
 		return nil, err
 	}
 	return resp, nil
 }
 
-// GetOrderStatus returns a list of Order structs
-func (ga *GeminiAPI) GetOrderStatus() ([]Order, error) {
+// GetActiveOrders returns a list of all live orders on the account
+func (ga *GeminiAPI) GetActiveOrders() ([]Order, error) {
+	return ga.GetActiveOrdersContext(context.Background())
+}
+
+// GetActiveOrdersContext is GetActiveOrders with a caller-supplied context
+func (ga *GeminiAPI) GetActiveOrdersContext(ctx context.Context) ([]Order, error) {
 	input := NewBaseRequest("/v1/orders")
 	orders := []Order{}
-	body, err := ga.AuthAPIReq(&input)
+	body, err := ga.AuthAPIReqContext(ctx, &input)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to get order status\n")
+		ga.logger.Errorf("Failed to get active orders\n")
 		return []Order{}, err
 	}
 	err = json.Unmarshal(body, &orders)
 	if err != nil {
-		ga.logger.Printf("ERROR: Failed to decode order status json\n")
+		ga.logger.Errorf("Failed to decode active orders json\n")
 		return []Order{}, err
 	}
 	return orders, nil
@@ -306,21 +363,35 @@ func (ga *GeminiAPI) GetOrderStatus() ([]Order, error) {
 
 // CancelAll attempts to cancel all open orders on the session
 func (ga *GeminiAPI) CancelAll() {
+	ga.CancelAllContext(context.Background())
+}
+
+// CancelAllContext is CancelAll with a caller-supplied context
+func (ga *GeminiAPI) CancelAllContext(ctx context.Context) {
 	input := NewBaseRequest("/v1/order/cancel/session")
-	ga.AuthAPIReq(&input)
+	ga.AuthAPIReqContext(ctx, &input)
 }
 
 // PlaceLimitOrder takes a direction, pair, client_id, amount, and price and returns an Order object
-func (ga *GeminiAPI) PlaceLimitOrder(side, pair, client_id string, amount, price float64, options []string) (Order, error) {
-	amountStr := fmt.Sprintf("%0.8f", amount)
-	priceStr := ""
-	if pair == "btcusd" || pair == "ethusd" {
-		priceStr = fmt.Sprintf("%0.2f", price)
-	} else if pair == "ethbtc" {
-		priceStr = fmt.Sprintf("%0.5f", price)
-	} else {
-		return Order{}, errors.New("Unsupported pair for placing orders")
+func (ga *GeminiAPI) PlaceLimitOrder(side, pair, client_id string, amount, price float64, options []LimitOrderOption) (Order, error) {
+	return ga.PlaceLimitOrderContext(context.Background(), side, pair, client_id, amount, price, options)
+}
+
+// PlaceLimitOrderContext is PlaceLimitOrder with a caller-supplied context
+func (ga *GeminiAPI) PlaceLimitOrderContext(ctx context.Context, side, pair, client_id string, amount, price float64, options []LimitOrderOption) (Order, error) {
+	details, err := ga.GetSymbolDetailsContext(ctx, pair)
+	if err != nil {
+		ga.logger.Errorf("Failed to get symbol details for pair %s\n", pair)
+		return Order{}, err
+	}
+	amountStr := fmt.Sprintf("%.*f", tickPrecision(details.TickSize), amount)
+	priceStr := fmt.Sprintf("%.*f", tickPrecision(details.QuoteIncrement), price)
+
+	optionStrs := make([]string, len(options))
+	for i, o := range options {
+		optionStrs[i] = o.String()
 	}
+
 	orderReq := &OrderPlaceReq{
 		BaseRequest: NewBaseRequest("/v1/order/new"),
 		Symbol:      pair,
@@ -329,36 +400,40 @@ func (ga *GeminiAPI) PlaceLimitOrder(side, pair, client_id string, amount, price
 		Side:        side,
 		Type:        "exchange limit",
 		ClientId:    client_id,
-		Options:     options,
+		Options:     optionStrs,
 	}
 
-	body, err := ga.AuthAPIReq(orderReq)
+	body, err := ga.AuthAPIReqContext(ctx, orderReq)
 	if err != nil {
-		ga.logger.Printf("ERROR: error placing order\n")
+		ga.logger.Errorf("error placing order\n")
 		return Order{}, err
 	}
 	order := Order{}
 	err = json.Unmarshal(body, &order)
 	if err != nil {
-		ga.logger.Printf("ERROR: error decoding order placement json response\n")
+		ga.logger.Errorf("error decoding order placement json response\n")
 		return Order{}, err
 	}
 	return order, nil
 }
 
-// NewGeminiAPI initializes a GeminiAPI object
-func NewGeminiAPI(baseurl, apikey, apisecret string, logger *log.Logger) *GeminiAPI {
-
-	if logger == nil {
-		logger = log.New(os.Stderr, "gemini api: ", log.Ldate|log.Ltime|log.Lshortfile)
+// NewGeminiAPI initializes a GeminiAPI object for the given environment.
+// Pass options to override the default logger, http.Client, or rate
+// limiters.
+func NewGeminiAPI(env Environment, apikey, apisecret string, opts ...Option) *GeminiAPI {
+	ga := &GeminiAPI{
+		BaseURL:        env.RESTBaseURL(),
+		ApiKey:         apikey,
+		ApiSecret:      apisecret,
+		logger:         newStdLogger(),
+		httpClient:     &http.Client{},
+		publicLimiter:  newDefaultPublicLimiter(),
+		privateLimiter: newDefaultPrivateLimiter(),
 	}
+	ga.Nonce.Store(time.Now().UnixNano())
 
-	ga := &GeminiAPI{
-		BaseURL:   baseurl,
-		ApiKey:    apikey,
-		ApiSecret: apisecret,
-		Nonce:     time.Now().UnixNano(),
-		logger:    logger,
+	for _, opt := range opts {
+		opt(ga)
 	}
 
 	return ga
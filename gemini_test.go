@@ -6,13 +6,12 @@ import (
 )
 
 const (
-	url       = "https://api.sandbox.gemini.com/"
 	apikey    = "<api key>"
 	apisecret = "<api secret>"
 )
 
 func TestTicker(t *testing.T) {
-	ga := NewGeminiAPI(url, "", "", nil)
+	ga := NewGeminiAPI(EnvSandbox, "", "")
 	_, err := ga.GetTicker("btcusd")
 	if err != nil {
 		t.Fail()
@@ -20,7 +19,7 @@ func TestTicker(t *testing.T) {
 }
 
 func TestOrderbook(t *testing.T) {
-	ga := NewGeminiAPI(url, "", "", nil)
+	ga := NewGeminiAPI(EnvSandbox, "", "")
 	_, err := ga.GetOrderbook("btcusd", 1, 1)
 	if err != nil {
 		fmt.Println(err)
@@ -29,35 +28,35 @@ func TestOrderbook(t *testing.T) {
 }
 
 func TestFunds(t *testing.T) {
-	ga := NewGeminiAPI(url, apikey, apisecret, nil)
+	ga := NewGeminiAPI(EnvSandbox, apikey, apisecret)
 	_, err := ga.GetFunds()
 	if err != nil {
 		t.Fail()
 	}
 }
 
-func TestOrderStatus(t *testing.T) {
-	ga := NewGeminiAPI(url, apikey, apisecret, nil)
-	_, err := ga.GetOrderStatus()
+func TestActiveOrders(t *testing.T) {
+	ga := NewGeminiAPI(EnvSandbox, apikey, apisecret)
+	_, err := ga.GetActiveOrders()
 	if err != nil {
 		t.Fail()
 	}
 }
 
 func TestPlaceLimitOrder(t *testing.T) {
-	ga := NewGeminiAPI(url, apikey, apisecret, nil)
-	_, err := ga.PlaceLimitOrder("buy", "btcusd", "order1", 1.0, 1.0, []string{"immediate-or-cancel"})
+	ga := NewGeminiAPI(EnvSandbox, apikey, apisecret)
+	_, err := ga.PlaceLimitOrder("buy", "btcusd", "order1", 1.0, 1.0, []LimitOrderOption{ImmediateOrCancel})
 	if err != nil {
 		t.Fail()
 	}
-	_, err = ga.PlaceLimitOrder("sell", "btcusd", "order1", 1.0, 1.0, []string{"immediate-or-cancel"})
+	_, err = ga.PlaceLimitOrder("sell", "btcusd", "order1", 1.0, 1.0, []LimitOrderOption{ImmediateOrCancel})
 	if err != nil {
 		t.Fail()
 	}
 }
 
 func TestWithdraw(t *testing.T) {
-	ga := NewGeminiAPI(url, apikey, apisecret, nil)
+	ga := NewGeminiAPI(EnvSandbox, apikey, apisecret)
 	_, err := ga.Withdraw("btc", "1DFCqM24Sg4mKJqXPDLmPsF2hCGZkXwVff", 0.1)
 	if err != nil {
 		t.Fail()
@@ -65,14 +64,14 @@ func TestWithdraw(t *testing.T) {
 }
 
 func TestBalances(t *testing.T) {
-	ga := NewGeminiAPI(url, apikey, apisecret, nil)
-	_, err := ga.GetBalance()
+	ga := NewGeminiAPI(EnvSandbox, apikey, apisecret)
+	_, err := ga.GetFunds()
 	if err != nil {
 		t.Fail()
 	}
 }
 
 func TestCancelAll(t *testing.T) {
-	ga := NewGeminiAPI(url, apikey, apisecret, nil)
+	ga := NewGeminiAPI(EnvSandbox, apikey, apisecret)
 	ga.CancelAll()
 }
@@ -0,0 +1,82 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// KlinePeriod is the candle time frame requested from Gemini's candles
+// endpoint.
+type KlinePeriod string
+
+const (
+	Kline1m  KlinePeriod = "1m"
+	Kline5m  KlinePeriod = "5m"
+	Kline15m KlinePeriod = "15m"
+	Kline30m KlinePeriod = "30m"
+	Kline1h  KlinePeriod = "1hr"
+	Kline6h  KlinePeriod = "6hr"
+	Kline1d  KlinePeriod = "1day"
+)
+
+// Kline stores a single OHLCV candle as returned by the candles endpoint.
+type Kline struct {
+	Timestamp time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+}
+
+// UnmarshalJSON decodes a single candle from Gemini's
+// [timestampms, open, high, low, close, volume] array form.
+func (k *Kline) UnmarshalJSON(data []byte) error {
+	raw := [6]float64{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	k.Timestamp = time.Unix(0, int64(raw[0])*int64(time.Millisecond))
+	k.Open = raw[1]
+	k.High = raw[2]
+	k.Low = raw[3]
+	k.Close = raw[4]
+	k.Volume = raw[5]
+	return nil
+}
+
+// GetKlineRecords takes a ticker pair, candle period, and returns up to
+// size Kline records at or after since, newest first.
+func (ga *GeminiAPI) GetKlineRecords(pair string, period KlinePeriod, size int, since time.Time) ([]Kline, error) {
+	return ga.GetKlineRecordsContext(context.Background(), pair, period, size, since)
+}
+
+// GetKlineRecordsContext is GetKlineRecords with a caller-supplied context
+func (ga *GeminiAPI) GetKlineRecordsContext(ctx context.Context, pair string, period KlinePeriod, size int, since time.Time) ([]Kline, error) {
+	candlesUrl := fmt.Sprintf("/v2/candles/%s/%s", pair, period)
+	body, err := ga.publicGet(ctx, candlesUrl)
+	if err != nil {
+		ga.logger.Errorf("Failed to get klines for pair %s\n", pair)
+		return []Kline{}, err
+	}
+	allKlines := []Kline{}
+	err = json.Unmarshal(body, &allKlines)
+	if err != nil {
+		ga.logger.Errorf("Failed to decode klines from response: %s\n", body)
+		return []Kline{}, err
+	}
+
+	klines := []Kline{}
+	for _, k := range allKlines {
+		if k.Timestamp.Before(since) {
+			continue
+		}
+		klines = append(klines, k)
+		if size > 0 && len(klines) >= size {
+			break
+		}
+	}
+	return klines, nil
+}
@@ -0,0 +1,53 @@
+package gemini
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestKlineUnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want Kline
+	}{
+		{
+			name: "typical candle",
+			data: `[1591516800000, 9084.49, 9118.0, 9063.51, 9099.13, 185.33]`,
+			want: Kline{
+				Timestamp: time.Unix(0, 1591516800000*int64(time.Millisecond)),
+				Open:      9084.49,
+				High:      9118.0,
+				Low:       9063.51,
+				Close:     9099.13,
+				Volume:    185.33,
+			},
+		},
+		{
+			name: "zeroed candle",
+			data: `[0, 0, 0, 0, 0, 0]`,
+			want: Kline{Timestamp: time.Unix(0, 0)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var k Kline
+			if err := json.Unmarshal([]byte(c.data), &k); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if !k.Timestamp.Equal(c.want.Timestamp) || k.Open != c.want.Open || k.High != c.want.High ||
+				k.Low != c.want.Low || k.Close != c.want.Close || k.Volume != c.want.Volume {
+				t.Errorf("got %+v, want %+v", k, c.want)
+			}
+		})
+	}
+}
+
+func TestKlineUnmarshalJSONInvalid(t *testing.T) {
+	var k Kline
+	if err := json.Unmarshal([]byte(`"not an array"`), &k); err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
@@ -0,0 +1,103 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// LimitOrderOption is an execution option that can be passed to
+// PlaceLimitOrder.
+type LimitOrderOption int
+
+const (
+	// PostOnly is an alias of MakerOrCancel; Gemini has a single option
+	// string for both.
+	PostOnly LimitOrderOption = iota
+	ImmediateOrCancel
+	FillOrKill
+	MakerOrCancel
+	AuctionOnly
+	Indication
+)
+
+// String returns the option string Gemini expects in the order's
+// "options" field.
+func (o LimitOrderOption) String() string {
+	switch o {
+	case PostOnly, MakerOrCancel:
+		return "maker-or-cancel"
+	case ImmediateOrCancel:
+		return "immediate-or-cancel"
+	case FillOrKill:
+		return "fill-or-kill"
+	case AuctionOnly:
+		return "auction-only"
+	case Indication:
+		return "indication-of-interest"
+	default:
+		return ""
+	}
+}
+
+// SymbolDetails stores the json returned by the symbols details endpoint,
+// describing the precision allowed for a trading pair.
+type SymbolDetails struct {
+	Symbol         string  `json:"symbol"`
+	BaseCurrency   string  `json:"base_currency"`
+	QuoteCurrency  string  `json:"quote_currency"`
+	TickSize       float64 `json:"tick_size"`
+	QuoteIncrement float64 `json:"quote_increment"`
+	MinOrderSize   float64 `json:"min_order_size,string"`
+}
+
+// GetSymbolDetails takes a ticker pair and returns its SymbolDetails,
+// fetching and caching the result from /v1/symbols/details/{symbol} on
+// first use.
+func (ga *GeminiAPI) GetSymbolDetails(pair string) (SymbolDetails, error) {
+	return ga.GetSymbolDetailsContext(context.Background(), pair)
+}
+
+// GetSymbolDetailsContext is GetSymbolDetails with a caller-supplied context
+func (ga *GeminiAPI) GetSymbolDetailsContext(ctx context.Context, pair string) (SymbolDetails, error) {
+	ga.symbolDetailsMu.RLock()
+	details, ok := ga.symbolDetails[pair]
+	ga.symbolDetailsMu.RUnlock()
+	if ok {
+		return details, nil
+	}
+
+	detailsUrl := fmt.Sprintf("/v1/symbols/details/%s", pair)
+	body, err := ga.publicGet(ctx, detailsUrl)
+	if err != nil {
+		ga.logger.Errorf("Failed to get symbol details for pair %s\n", pair)
+		return SymbolDetails{}, err
+	}
+	err = json.Unmarshal(body, &details)
+	if err != nil {
+		ga.logger.Errorf("Failed to decode symbol details from response: %s\n", body)
+		return SymbolDetails{}, err
+	}
+
+	ga.symbolDetailsMu.Lock()
+	if ga.symbolDetails == nil {
+		ga.symbolDetails = map[string]SymbolDetails{}
+	}
+	ga.symbolDetails[pair] = details
+	ga.symbolDetailsMu.Unlock()
+	return details, nil
+}
+
+// tickPrecision returns the number of decimal places needed to represent
+// the given tick size, e.g. 0.01 -> 2, 1e-8 -> 8.
+func tickPrecision(tick float64) int {
+	if tick <= 0 {
+		return 8
+	}
+	places := 0
+	for tick < 1 && places < 12 {
+		tick *= 10
+		places++
+	}
+	return places
+}
@@ -0,0 +1,44 @@
+package gemini
+
+import "testing"
+
+func TestLimitOrderOptionString(t *testing.T) {
+	cases := []struct {
+		option LimitOrderOption
+		want   string
+	}{
+		{PostOnly, "maker-or-cancel"},
+		{MakerOrCancel, "maker-or-cancel"},
+		{ImmediateOrCancel, "immediate-or-cancel"},
+		{FillOrKill, "fill-or-kill"},
+		{AuctionOnly, "auction-only"},
+		{Indication, "indication-of-interest"},
+		{LimitOrderOption(99), ""},
+	}
+
+	for _, c := range cases {
+		if got := c.option.String(); got != c.want {
+			t.Errorf("LimitOrderOption(%d).String() = %q, want %q", c.option, got, c.want)
+		}
+	}
+}
+
+func TestTickPrecision(t *testing.T) {
+	cases := []struct {
+		tick float64
+		want int
+	}{
+		{0.01, 2},
+		{1e-8, 8},
+		{1, 0},
+		{0, 8},
+		{-1, 8},
+		{0.1, 1},
+	}
+
+	for _, c := range cases {
+		if got := tickPrecision(c.tick); got != c.want {
+			t.Errorf("tickPrecision(%v) = %d, want %d", c.tick, got, c.want)
+		}
+	}
+}
@@ -5,8 +5,52 @@ import (
 	"os"
 )
 
-var logger *log.Logger
+// Logger is a pluggable leveled logger. Callers can adapt logrus, zap,
+// zerolog, or similar to this interface via WithLogger; the default
+// implementation wraps the standard library logger and suppresses Debugf
+// output.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// LogLevel gates which calls to the default Logger actually reach stderr.
+// Use it with WithLogLevel.
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = iota
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// stdLogger is the default Logger, backed by the standard library's
+// *log.Logger. It drops anything below LogLevelInfo so that payload
+// dumps from Debugf don't leak to stderr unless a caller opts in with
+// WithLogLevel.
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+func newStdLogger() *stdLogger {
+	return &stdLogger{
+		logger: log.New(os.Stderr, "gemini api: ", log.Ldate|log.Ltime|log.Lshortfile),
+		level:  LogLevelInfo,
+	}
+}
+
+func (l *stdLogger) Debugf(format string, args ...interface{}) { l.logf(LogLevelDebug, "DEBUG: "+format, args...) }
+func (l *stdLogger) Infof(format string, args ...interface{})  { l.logf(LogLevelInfo, format, args...) }
+func (l *stdLogger) Warnf(format string, args ...interface{})  { l.logf(LogLevelWarn, "WARN: "+format, args...) }
+func (l *stdLogger) Errorf(format string, args ...interface{}) { l.logf(LogLevelError, "ERROR: "+format, args...) }
 
-func init() {
-	logger = log.New(os.Stderr, "gemini api: ", log.Ldate | log.Ltime | log.Lshortfile)
+func (l *stdLogger) logf(level LogLevel, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+	l.logger.Printf(format, args...)
 }
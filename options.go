@@ -0,0 +1,51 @@
+package gemini
+
+import "net/http"
+
+// Option configures optional behavior on a GeminiAPI, applied in
+// NewGeminiAPI.
+type Option func(*GeminiAPI)
+
+// WithHTTPClient configures the *http.Client used for every request,
+// instead of the default client constructed by NewGeminiAPI.
+func WithHTTPClient(client *http.Client) Option {
+	return func(ga *GeminiAPI) {
+		ga.httpClient = client
+	}
+}
+
+// WithLogger configures the Logger used for every request, instead of the
+// default logger constructed by NewGeminiAPI, which logs to stderr and
+// suppresses Debugf output.
+func WithLogger(logger Logger) Option {
+	return func(ga *GeminiAPI) {
+		ga.logger = logger
+	}
+}
+
+// WithLogLevel sets the minimum LogLevel reported by the default logger
+// constructed by NewGeminiAPI. It has no effect if combined with
+// WithLogger, since that replaces the default logger entirely.
+func WithLogLevel(level LogLevel) Option {
+	return func(ga *GeminiAPI) {
+		if l, ok := ga.logger.(*stdLogger); ok {
+			l.level = level
+		}
+	}
+}
+
+// WithPublicRateLimiter overrides the RateLimiter used for unauthenticated
+// requests such as GetTicker and GetOrderbook.
+func WithPublicRateLimiter(rl RateLimiter) Option {
+	return func(ga *GeminiAPI) {
+		ga.publicLimiter = rl
+	}
+}
+
+// WithPrivateRateLimiter overrides the RateLimiter used for authenticated
+// requests made through AuthAPIReq.
+func WithPrivateRateLimiter(rl RateLimiter) Option {
+	return func(ga *GeminiAPI) {
+		ga.privateLimiter = rl
+	}
+}
@@ -0,0 +1,143 @@
+package gemini
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Trade stores the json returned by the mytrades endpoint
+type Trade struct {
+	TID         int64   `json:"tid"`
+	OrderID     string  `json:"order_id"`
+	Price       float64 `json:"price,string"`
+	Amount      float64 `json:"amount,string"`
+	Fee         float64 `json:"fee_amount,string"`
+	FeeCurrency string  `json:"fee_currency"`
+	Timestamp   int     `json:"timestamp"`
+	TimestampMs int64   `json:"timestampms"`
+	Aggressor   bool    `json:"aggressor"`
+	IsMaker     bool    `json:"-"`
+}
+
+type OrderStatusReq struct {
+	BaseRequest
+	OrderId string `json:"order_id"`
+}
+
+type OrderCancelReq struct {
+	BaseRequest
+	OrderId string `json:"order_id"`
+}
+
+type PastTradesReq struct {
+	BaseRequest
+	Symbol      string `json:"symbol"`
+	LimitTrades int    `json:"limit_trades,omitempty"`
+	Timestamp   int64  `json:"timestamp,omitempty"`
+}
+
+// GetOrderStatus returns the Order for a single order id
+func (ga *GeminiAPI) GetOrderStatus(orderId string) (Order, error) {
+	return ga.GetOrderStatusContext(context.Background(), orderId)
+}
+
+// GetOrderStatusContext is GetOrderStatus with a caller-supplied context
+func (ga *GeminiAPI) GetOrderStatusContext(ctx context.Context, orderId string) (Order, error) {
+	input := &OrderStatusReq{
+		BaseRequest: NewBaseRequest("/v1/order/status"),
+		OrderId:     orderId,
+	}
+	order := Order{}
+	body, err := ga.AuthAPIReqContext(ctx, input)
+	if err != nil {
+		ga.logger.Errorf("Failed to get order status for order %s\n", orderId)
+		return order, err
+	}
+	err = json.Unmarshal(body, &order)
+	if err != nil {
+		ga.logger.Errorf("Failed to decode order status json\n")
+		return order, err
+	}
+	return order, nil
+}
+
+// CancelOrder cancels a single open order by id
+func (ga *GeminiAPI) CancelOrder(orderId string) (Order, error) {
+	return ga.CancelOrderContext(context.Background(), orderId)
+}
+
+// CancelOrderContext is CancelOrder with a caller-supplied context
+func (ga *GeminiAPI) CancelOrderContext(ctx context.Context, orderId string) (Order, error) {
+	input := &OrderCancelReq{
+		BaseRequest: NewBaseRequest("/v1/order/cancel"),
+		OrderId:     orderId,
+	}
+	order := Order{}
+	body, err := ga.AuthAPIReqContext(ctx, input)
+	if err != nil {
+		ga.logger.Errorf("Failed to cancel order %s\n", orderId)
+		return order, err
+	}
+	err = json.Unmarshal(body, &order)
+	if err != nil {
+		ga.logger.Errorf("Failed to decode cancel order json\n")
+		return order, err
+	}
+	return order, nil
+}
+
+// GetPastTrades returns up to limit past trades for symbol at or after
+// since. A limit of 0 asks Gemini for its default page size.
+func (ga *GeminiAPI) GetPastTrades(symbol string, limit int, since time.Time) ([]Trade, error) {
+	return ga.GetPastTradesContext(context.Background(), symbol, limit, since)
+}
+
+// GetPastTradesContext is GetPastTrades with a caller-supplied context
+func (ga *GeminiAPI) GetPastTradesContext(ctx context.Context, symbol string, limit int, since time.Time) ([]Trade, error) {
+	input := &PastTradesReq{
+		BaseRequest: NewBaseRequest("/v1/mytrades"),
+		Symbol:      symbol,
+		LimitTrades: limit,
+	}
+	if !since.IsZero() {
+		input.Timestamp = since.Unix()
+	}
+	trades := []Trade{}
+	body, err := ga.AuthAPIReqContext(ctx, input)
+	if err != nil {
+		ga.logger.Errorf("Failed to get past trades for symbol %s\n", symbol)
+		return trades, err
+	}
+	err = json.Unmarshal(body, &trades)
+	if err != nil {
+		ga.logger.Errorf("Failed to decode past trades json\n")
+		return trades, err
+	}
+	for i := range trades {
+		trades[i].IsMaker = !trades[i].Aggressor
+	}
+	return trades, nil
+}
+
+// ReplaceOrder changes the price and amount of an existing order. Gemini
+// has no native order replacement endpoint, so this cancels the existing
+// order and re-posts it with the new price and amount, preserving its
+// side, symbol, and client order id.
+func (ga *GeminiAPI) ReplaceOrder(orderId string, newPrice, newAmount float64) (Order, error) {
+	return ga.ReplaceOrderContext(context.Background(), orderId, newPrice, newAmount)
+}
+
+// ReplaceOrderContext is ReplaceOrder with a caller-supplied context
+func (ga *GeminiAPI) ReplaceOrderContext(ctx context.Context, orderId string, newPrice, newAmount float64) (Order, error) {
+	existing, err := ga.GetOrderStatusContext(ctx, orderId)
+	if err != nil {
+		ga.logger.Errorf("Failed to look up order %s for replacement\n", orderId)
+		return Order{}, err
+	}
+	if _, err := ga.CancelOrderContext(ctx, orderId); err != nil {
+		ga.logger.Errorf("Failed to cancel order %s for replacement\n", orderId)
+		return Order{}, err
+	}
+	return ga.PlaceLimitOrderContext(ctx, existing.Side, existing.Symbol, existing.ClientId, newAmount, newPrice, nil)
+}
@@ -0,0 +1,79 @@
+package gemini
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is consulted before every request GeminiAPI makes, so a
+// caller can plug in their own throttling strategy. Wait should block
+// until a request may proceed, or return ctx.Err() if ctx is cancelled
+// first.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// tokenBucketLimiter is a simple token bucket RateLimiter. It is used as
+// the default limiter for both the public and private API, sized to
+// Gemini's documented rate limits.
+type tokenBucketLimiter struct {
+	mu      sync.Mutex
+	tokens  float64
+	max     float64
+	refill  float64 // tokens added per second
+	lastFed time.Time
+}
+
+// NewTokenBucketLimiter returns a RateLimiter that allows ratePerSecond
+// requests per second on average, with bursts up to burst requests.
+func NewTokenBucketLimiter(ratePerSecond float64, burst int) RateLimiter {
+	return &tokenBucketLimiter{
+		tokens:  float64(burst),
+		max:     float64(burst),
+		refill:  ratePerSecond,
+		lastFed: time.Now(),
+	}
+}
+
+func (l *tokenBucketLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.lastFed).Seconds() * l.refill
+		if l.tokens > l.max {
+			l.tokens = l.max
+		}
+		l.lastFed = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.refill * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Gemini's documented rate limits: 120 requests/minute for the public
+// API, 600 requests/minute for the private, authenticated API.
+const (
+	publicRatePerSecond  = 120.0 / 60.0
+	privateRatePerSecond = 600.0 / 60.0
+)
+
+func newDefaultPublicLimiter() RateLimiter {
+	return NewTokenBucketLimiter(publicRatePerSecond, 5)
+}
+
+func newDefaultPrivateLimiter() RateLimiter {
+	return NewTokenBucketLimiter(privateRatePerSecond, 10)
+}
@@ -0,0 +1,52 @@
+package gemini
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketLimiterBurst(t *testing.T) {
+	rl := NewTokenBucketLimiter(1, 3)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		if err := rl.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error on burst request %d: %s", i, err)
+		}
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Errorf("burst request %d took %s, want near-instant", i, elapsed)
+		}
+	}
+}
+
+func TestTokenBucketLimiterThrottles(t *testing.T) {
+	rl := NewTokenBucketLimiter(20, 1)
+	ctx := context.Background()
+
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("unexpected error on first request: %s", err)
+	}
+
+	start := time.Now()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("unexpected error on second request: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("second request returned after %s, want it to wait for a refill", elapsed)
+	}
+}
+
+func TestTokenBucketLimiterContextCancelled(t *testing.T) {
+	rl := NewTokenBucketLimiter(1, 1)
+	ctx := context.Background()
+	if err := rl.Wait(ctx); err != nil {
+		t.Fatalf("unexpected error draining the bucket: %s", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := rl.Wait(cancelCtx); err != context.Canceled {
+		t.Errorf("Wait() = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,361 @@
+package gemini
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MarketEventType identifies the kind of event delivered on a market data
+// stream.
+type MarketEventType string
+
+const (
+	MarketEventTrade   MarketEventType = "trade"
+	MarketEventChange  MarketEventType = "change"
+	MarketEventAuction MarketEventType = "auction"
+)
+
+// MarketEvent stores a single event from the /v1/marketdata/{symbol}
+// websocket feed. Not every field is populated for every Type.
+type MarketEvent struct {
+	Type        MarketEventType `json:"type"`
+	Side        string          `json:"side"`
+	Price       float64         `json:"price,string"`
+	Remaining   float64         `json:"remaining,string"`
+	Delta       float64         `json:"delta,string"`
+	Reason      string          `json:"reason"`
+	TID         int64           `json:"tid"`
+	Amount      float64         `json:"amount,string"`
+	MakerSide   string          `json:"makerSide"`
+	EventId     int64           `json:"eventId"`
+	Timestampms int64           `json:"timestampms"`
+}
+
+type marketDataMessage struct {
+	Type        string        `json:"type"`
+	EventId     int64         `json:"eventId"`
+	Events      []MarketEvent `json:"events"`
+	Timestampms int64         `json:"timestampms"`
+}
+
+// OrderEventType identifies the kind of event delivered on the private
+// order events stream.
+type OrderEventType string
+
+const (
+	OrderEventSubscriptionAck OrderEventType = "subscription_ack"
+	OrderEventHeartbeat       OrderEventType = "heartbeat"
+	OrderEventInitial         OrderEventType = "initial"
+	OrderEventAccepted        OrderEventType = "accepted"
+	OrderEventRejected        OrderEventType = "rejected"
+	OrderEventBooked          OrderEventType = "booked"
+	OrderEventFill            OrderEventType = "fill"
+	OrderEventCancelled       OrderEventType = "cancelled"
+	OrderEventCancelRejected  OrderEventType = "cancel_rejected"
+	OrderEventClosed          OrderEventType = "closed"
+)
+
+// OrderEvent stores a single event from the /v1/order/events websocket
+// feed. Not every field is populated for every Type.
+type OrderEvent struct {
+	Type            OrderEventType `json:"type"`
+	OrderId         string         `json:"order_id"`
+	ClientId        string         `json:"client_order_id"`
+	Symbol          string         `json:"symbol"`
+	Side            string         `json:"side"`
+	OrderType       string         `json:"order_type"`
+	Price           float64        `json:"price,string"`
+	ExecutedAmount  float64        `json:"executed_amount,string"`
+	RemainingAmount float64        `json:"remaining_amount,string"`
+	OrigAmount      float64        `json:"original_amount,string"`
+	AvgExecPrice    float64        `json:"avg_execution_price,string"`
+	Behavior        string         `json:"behavior"`
+	Reason          string         `json:"reason"`
+	Timestampms     int64          `json:"timestampms"`
+}
+
+const (
+	streamInitialBackoff = 500 * time.Millisecond
+	streamMaxBackoff     = 30 * time.Second
+)
+
+// GeminiStream connects to Gemini's websocket feeds and delivers typed
+// market data and order events. Unlike GeminiAPI it keeps long lived
+// connections open and reconnects automatically, with exponential backoff,
+// if a feed drops.
+type GeminiStream struct {
+	BaseURL   string
+	ApiKey    string
+	ApiSecret string
+	logger    Logger
+
+	closeOnce sync.Once
+	done      chan struct{}
+
+	connsMu sync.Mutex
+	conns   map[*websocket.Conn]struct{}
+}
+
+// NewGeminiStream initializes a GeminiStream object for the given
+// environment. If logger is nil, a default logger that logs to stderr
+// and suppresses Debugf output is used.
+func NewGeminiStream(env Environment, apikey, apisecret string, logger Logger) *GeminiStream {
+	if logger == nil {
+		logger = newStdLogger()
+	}
+
+	return &GeminiStream{
+		BaseURL:   env.WSBaseURL(),
+		ApiKey:    apikey,
+		ApiSecret: apisecret,
+		logger:    logger,
+		done:      make(chan struct{}),
+		conns:     map[*websocket.Conn]struct{}{},
+	}
+}
+
+// Close stops every subscription started on gs: it closes the underlying
+// connections, which unblocks any in-flight read and stops the reconnect
+// loop, and closes each subscription's event channel. Close may be called
+// more than once.
+func (gs *GeminiStream) Close() {
+	gs.closeOnce.Do(func() {
+		close(gs.done)
+		gs.connsMu.Lock()
+		for conn := range gs.conns {
+			conn.Close()
+		}
+		gs.connsMu.Unlock()
+	})
+}
+
+// trackConn registers conn so Close can close it. A conn dialed after Close
+// has already swept the set is closed right away instead.
+func (gs *GeminiStream) trackConn(conn *websocket.Conn) {
+	gs.connsMu.Lock()
+	if gs.closed() {
+		conn.Close()
+	}
+	gs.conns[conn] = struct{}{}
+	gs.connsMu.Unlock()
+}
+
+func (gs *GeminiStream) untrackConn(conn *websocket.Conn) {
+	gs.connsMu.Lock()
+	delete(gs.conns, conn)
+	gs.connsMu.Unlock()
+}
+
+// closed reports whether Close has been called.
+func (gs *GeminiStream) closed() bool {
+	select {
+	case <-gs.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// waitBackoff sleeps for d, returning early with false if Close is called
+// first.
+func (gs *GeminiStream) waitBackoff(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-gs.done:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// SubscribeMarket connects to the public market data feed for pair and
+// returns a channel of MarketEvent. The channel is closed once Close is
+// called; until then the connection is transparently redialed on error.
+func (gs *GeminiStream) SubscribeMarket(pair string) (<-chan MarketEvent, error) {
+	url := fmt.Sprintf("%s/v1/marketdata/%s", gs.BaseURL, pair)
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		gs.logger.Errorf("failed to connect to market data feed for pair %s\n", pair)
+		return nil, err
+	}
+	gs.trackConn(conn)
+
+	out := make(chan MarketEvent)
+	go gs.runMarket(url, conn, out)
+	return out, nil
+}
+
+func (gs *GeminiStream) runMarket(url string, conn *websocket.Conn, out chan<- MarketEvent) {
+	defer close(out)
+	backoff := streamInitialBackoff
+	for {
+		msg := marketDataMessage{}
+		err := conn.ReadJSON(&msg)
+		if err != nil {
+			conn.Close()
+			gs.untrackConn(conn)
+			if gs.closed() {
+				return
+			}
+			gs.logger.Errorf("market data feed read failed, reconnecting in %s: %s\n", backoff, err)
+			conn, backoff = gs.redialMarket(url, backoff)
+			if conn == nil {
+				return
+			}
+			continue
+		}
+		backoff = streamInitialBackoff
+		for _, event := range msg.Events {
+			event.EventId = msg.EventId
+			event.Timestampms = msg.Timestampms
+			select {
+			case out <- event:
+			case <-gs.done:
+				return
+			}
+		}
+	}
+}
+
+// redialMarket retries the market data dial with exponential backoff until
+// it succeeds or Close is called, in which case it returns a nil conn.
+func (gs *GeminiStream) redialMarket(url string, backoff time.Duration) (*websocket.Conn, time.Duration) {
+	for {
+		if !gs.waitBackoff(backoff) {
+			return nil, backoff
+		}
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			gs.logger.Errorf("market data feed reconnect failed, retrying in %s: %s\n", backoff, err)
+			backoff = nextStreamBackoff(backoff)
+			continue
+		}
+		gs.trackConn(conn)
+		return conn, streamInitialBackoff
+	}
+}
+
+// SubscribeOrders connects to the private order events feed, performing
+// the HMAC-SHA384 signed handshake required to authenticate the
+// connection, and returns a channel of OrderEvent.
+func (gs *GeminiStream) SubscribeOrders() (<-chan OrderEvent, error) {
+	route := "/v1/order/events"
+	url := fmt.Sprintf("%s%s", gs.BaseURL, route)
+	header, err := gs.authHeader(route)
+	if err != nil {
+		gs.logger.Errorf("failed to build order events auth header\n")
+		return nil, err
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, header)
+	if err != nil {
+		gs.logger.Errorf("failed to connect to order events feed\n")
+		return nil, err
+	}
+	gs.trackConn(conn)
+
+	out := make(chan OrderEvent)
+	go gs.runOrders(url, conn, out)
+	return out, nil
+}
+
+func (gs *GeminiStream) runOrders(url string, conn *websocket.Conn, out chan<- OrderEvent) {
+	defer close(out)
+	backoff := streamInitialBackoff
+	for {
+		events := []OrderEvent{}
+		err := conn.ReadJSON(&events)
+		if err != nil {
+			conn.Close()
+			gs.untrackConn(conn)
+			if gs.closed() {
+				return
+			}
+			gs.logger.Errorf("order events feed read failed, reconnecting in %s: %s\n", backoff, err)
+			conn, backoff = gs.redialOrders(url, backoff)
+			if conn == nil {
+				return
+			}
+			continue
+		}
+		backoff = streamInitialBackoff
+		for _, event := range events {
+			select {
+			case out <- event:
+			case <-gs.done:
+				return
+			}
+		}
+	}
+}
+
+// redialOrders retries the order events dial, re-signing the handshake
+// each attempt, with exponential backoff until it succeeds or Close is
+// called, in which case it returns a nil conn.
+func (gs *GeminiStream) redialOrders(url string, backoff time.Duration) (*websocket.Conn, time.Duration) {
+	for {
+		if !gs.waitBackoff(backoff) {
+			return nil, backoff
+		}
+		header, err := gs.authHeader("/v1/order/events")
+		if err != nil {
+			gs.logger.Errorf("order events feed reconnect failed to build auth header, retrying in %s: %s\n", backoff, err)
+			backoff = nextStreamBackoff(backoff)
+			continue
+		}
+		conn, _, err := websocket.DefaultDialer.Dial(url, header)
+		if err != nil {
+			gs.logger.Errorf("order events feed reconnect failed, retrying in %s: %s\n", backoff, err)
+			backoff = nextStreamBackoff(backoff)
+			continue
+		}
+		gs.trackConn(conn)
+		return conn, streamInitialBackoff
+	}
+}
+
+// authHeader builds the X-GEMINI-* headers required to authenticate the
+// private order events websocket handshake.
+func (gs *GeminiStream) authHeader(route string) (http.Header, error) {
+	payload, err := json.Marshal(&BaseRequest{
+		Request: route,
+		Nonce:   time.Now().UnixNano(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	base64Payload, sig := signGeminiPayload(gs.ApiSecret, payload)
+
+	header := http.Header{}
+	header.Add("X-GEMINI-APIKEY", gs.ApiKey)
+	header.Add("X-GEMINI-PAYLOAD", base64Payload)
+	header.Add("X-GEMINI-SIGNATURE", sig)
+	return header, nil
+}
+
+func nextStreamBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff > streamMaxBackoff {
+		backoff = streamMaxBackoff
+	}
+	return backoff
+}
+
+// signGeminiPayload base64-encodes payload and signs it with secret using
+// HMAC-SHA384, as required by Gemini's private API and websocket
+// authentication.
+func signGeminiPayload(secret string, payload []byte) (base64Payload, signature string) {
+	base64Payload = base64.StdEncoding.EncodeToString(payload)
+	h := hmac.New(sha512.New384, []byte(secret))
+	h.Write([]byte(base64Payload))
+	return base64Payload, hex.EncodeToString(h.Sum(nil))
+}